@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Alert is a single active weather alert, shaped after NWS's
+// FeatureCollection/FeatureProperties alert schema.
+type Alert struct {
+	Event       string
+	Severity    string
+	Description string
+	Instruction string
+}
+
+// nwsAlertsResponse is the subset of /alerts/active we need.
+type nwsAlertsResponse struct {
+	Features []struct {
+		Properties struct {
+			Event       string `json:"event"`
+			Severity    string `json:"severity"`
+			Description string `json:"description"`
+			Instruction string `json:"instruction"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// alertsFreshnessWindow is short: alerts can be issued or cancelled at
+// any time, so we don't want to sit on a stale one for long.
+const alertsFreshnessWindow = 5 * time.Minute
+
+// alertsCache caches active alerts for a single lat/lon, independent of
+// the current-conditions and forecast caches.
+type alertsCache struct {
+	alerts      []Alert
+	lastFetched time.Time
+	mu          sync.RWMutex
+}
+
+var aCache = &alertsCache{}
+
+// alertsDisabled reports whether the NO_ALERTS env var has been set,
+// turning off the alerts subsystem entirely.
+func alertsDisabled() bool {
+	_, ok := os.LookupEnv("NO_ALERTS")
+	return ok
+}
+
+// getCachedAlerts returns the cached active alerts for the given lat/lon,
+// fetching new ones if the cache is stale. A fetch failure never
+// propagates past a logged warning and the last-known (possibly empty)
+// alerts, so it can never block rendering of current conditions.
+func getCachedAlerts(lat, lon float64) []Alert {
+	if alertsDisabled() {
+		return nil
+	}
+
+	aCache.mu.RLock()
+	fresh := !aCache.lastFetched.IsZero() && time.Since(aCache.lastFetched) < alertsFreshnessWindow
+	alerts := aCache.alerts
+	aCache.mu.RUnlock()
+	if fresh {
+		return alerts
+	}
+
+	fetched, err := fetchActiveAlerts(context.Background(), lat, lon)
+	if err != nil {
+		log.Printf("Warning: could not fetch weather alerts: %v", err)
+		return alerts
+	}
+
+	aCache.mu.Lock()
+	aCache.alerts = fetched
+	aCache.lastFetched = time.Now()
+	aCache.mu.Unlock()
+
+	return fetched
+}
+
+// fetchActiveAlerts fetches active NWS alerts for a point, regardless of
+// which backend is configured for current conditions/forecast.
+func fetchActiveAlerts(ctx context.Context, lat, lon float64) ([]Alert, error) {
+	url := fmt.Sprintf("https://api.weather.gov/alerts/active?point=%.4f,%.4f", lat, lon)
+
+	var response nwsAlertsResponse
+	if err := nwsGetJSON(ctx, url, &response); err != nil {
+		return nil, fmt.Errorf("error fetching NWS alerts: %v", err)
+	}
+
+	alerts := make([]Alert, 0, len(response.Features))
+	for _, f := range response.Features {
+		alerts = append(alerts, Alert{
+			Event:       f.Properties.Event,
+			Severity:    f.Properties.Severity,
+			Description: f.Properties.Description,
+			Instruction: f.Properties.Instruction,
+		})
+	}
+
+	return alerts, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// registerAPIHandlers wires up the JSON API endpoints that mirror the
+// data rendered on "/", split out so dashboards and home-automation
+// clients have a cheap polling path that doesn't require scraping HTML.
+func registerAPIHandlers() {
+	http.HandleFunc("/api/current", func(w http.ResponseWriter, r *http.Request) {
+		obs, err := getCachedWeatherData()
+		if err != nil {
+			log.Printf("Error getting weather data for /api/current: %v", err)
+			http.Error(w, "Weather data unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		cache.mu.RLock()
+		dataAge := cache.dataAge
+		cache.mu.RUnlock()
+		serveJSONWithCaching(w, r, obs, dataAge)
+	})
+
+	http.HandleFunc("/api/forecast", func(w http.ResponseWriter, r *http.Request) {
+		forecast, err := getCachedForecast()
+		if err != nil {
+			log.Printf("Error getting forecast data for /api/forecast: %v", err)
+			http.Error(w, "Forecast data unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fCache.mu.RLock()
+		lastFetched := fCache.lastFetched
+		fCache.mu.RUnlock()
+		serveJSONWithCaching(w, r, forecast, lastFetched)
+	})
+
+	http.HandleFunc("/api/alerts", func(w http.ResponseWriter, r *http.Request) {
+		obs, err := getCachedWeatherData()
+		if err != nil {
+			log.Printf("Error getting weather data for /api/alerts: %v", err)
+			http.Error(w, "Weather data unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		alerts := getCachedAlerts(obs.Lat, obs.Lon)
+		aCache.mu.RLock()
+		lastFetched := aCache.lastFetched
+		aCache.mu.RUnlock()
+		serveJSONWithCaching(w, r, alerts, lastFetched)
+	})
+}
+
+// serveJSONWithCaching marshals payload to JSON and serves it with
+// Last-Modified/ETag headers, honoring If-Modified-Since and
+// If-None-Match with a 304 when the client's copy is already current.
+func serveJSONWithCaching(w http.ResponseWriter, r *http.Request, payload interface{}, lastModified time.Time) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, "error marshaling response", http.StatusInternalServerError)
+		return
+	}
+
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(body))
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" && match == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		// http.TimeFormat/http.ParseTime truncate to whole seconds, so
+		// comparing with After against a sub-second lastModified would
+		// never be satisfied. Compare the way net/http's own conditional
+		// request handling does: lastModified.Before(t + 1s).
+		if t, err := http.ParseTime(ims); err == nil && lastModified.Before(t.Add(time.Second)) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
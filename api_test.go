@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServeJSONWithCachingIfNoneMatch(t *testing.T) {
+	lastModified := time.Now()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/current", nil)
+	serveJSONWithCaching(rec, req, map[string]int{"a": 1}, lastModified)
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header to be set")
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/current", nil)
+	req.Header.Set("If-None-Match", etag)
+	serveJSONWithCaching(rec, req, map[string]int{"a": 1}, lastModified)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match match: got status %d, want %d", rec.Code, http.StatusNotModified)
+	}
+}
+
+func TestServeJSONWithCachingIfModifiedSince(t *testing.T) {
+	// lastModified carries a sub-second component, like time.Now() does
+	// in the real cache structs. http.TimeFormat truncates to whole
+	// seconds, so a naive After() comparison would never consider the
+	// round-tripped time "not modified since".
+	lastModified := time.Date(2024, 1, 1, 12, 0, 0, 500_000_000, time.UTC)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/current", nil)
+	req.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+	serveJSONWithCaching(rec, req, map[string]int{"a": 1}, lastModified)
+
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("same-second If-Modified-Since: got status %d, want %d", rec.Code, http.StatusNotModified)
+	}
+}
+
+func TestServeJSONWithCachingModifiedAfter(t *testing.T) {
+	lastModified := time.Date(2024, 1, 1, 12, 0, 1, 0, time.UTC)
+	since := lastModified.Add(-time.Minute)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/current", nil)
+	req.Header.Set("If-Modified-Since", since.Format(http.TimeFormat))
+	serveJSONWithCaching(rec, req, map[string]int{"a": 1}, lastModified)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("data modified after If-Modified-Since: got status %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestServeJSONWithCachingNoConditionalHeaders(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/current", nil)
+	serveJSONWithCaching(rec, req, map[string]int{"a": 1}, time.Now())
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("no conditional headers: got status %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.Len() == 0 {
+		t.Error("expected a JSON body to be written")
+	}
+}
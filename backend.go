@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WeatherBackend fetches the current observation from a single upstream
+// weather provider.
+type WeatherBackend interface {
+	Fetch(ctx context.Context) (Observation, error)
+}
+
+// readBackendName determines which WeatherBackend to use, preferring the
+// BACKEND env var and falling back to the /mnt/secrets/backend file. It
+// defaults to "wunderground" to preserve existing deployments.
+func readBackendName() string {
+	if name := os.Getenv("BACKEND"); name != "" {
+		return strings.ToLower(strings.TrimSpace(name))
+	}
+	if content, err := os.ReadFile("/mnt/secrets/backend"); err == nil {
+		if name := strings.ToLower(strings.TrimSpace(string(content))); name != "" {
+			return name
+		}
+	}
+	return "wunderground"
+}
+
+// newBackend constructs the configured WeatherBackend, reading whatever
+// provider-specific secrets that backend requires.
+func newBackend(name string) (WeatherBackend, error) {
+	switch name {
+	case "wunderground":
+		return newWundergroundBackend()
+	case "nws":
+		return newNWSBackend()
+	case "openweathermap":
+		return newOpenWeatherMapBackend()
+	default:
+		return nil, fmt.Errorf("unknown weather backend %q", name)
+	}
+}
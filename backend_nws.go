@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const nwsUserAgent = "ocp-pws (https://github.com/methridge/ocp-pws)"
+
+// nwsPoints is the subset of api.weather.gov's /points/{lat},{lon}
+// response we need to find the nearest observation station and the
+// gridpoint forecast URL.
+type nwsPoints struct {
+	Properties struct {
+		ObservationStations string `json:"observationStations"`
+		Forecast            string `json:"forecast"`
+	} `json:"properties"`
+}
+
+// nwsForecast is the subset of a gridpoint's /forecast response we need.
+type nwsForecast struct {
+	Properties struct {
+		Periods []struct {
+			Name          string `json:"name"`
+			StartTime     string `json:"startTime"`
+			IsDaytime     bool   `json:"isDaytime"`
+			Temperature   int    `json:"temperature"`
+			ShortForecast string `json:"shortForecast"`
+			WindSpeed     string `json:"windSpeed"`
+			WindDirection string `json:"windDirection"`
+		} `json:"periods"`
+	} `json:"properties"`
+}
+
+// nwsStations is the subset of the observationStations collection
+// response we need: an ordered list of nearby station IDs.
+type nwsStations struct {
+	ObservationStations []string `json:"observationStations"`
+}
+
+// nwsObservation is the subset of a station's /observations/latest
+// response we need, in NWS's native SI-ish units (temperature in
+// Celsius, speed in km/h, pressure in Pa).
+type nwsObservation struct {
+	Properties struct {
+		Timestamp             string      `json:"timestamp"`
+		Temperature           nwsQuantity `json:"temperature"`
+		HeatIndex             nwsQuantity `json:"heatIndex"`
+		WindChill             nwsQuantity `json:"windChill"`
+		Dewpoint              nwsQuantity `json:"dewpoint"`
+		RelativeHumidity      nwsQuantity `json:"relativeHumidity"`
+		WindSpeed             nwsQuantity `json:"windSpeed"`
+		WindGust              nwsQuantity `json:"windGust"`
+		WindDirection         nwsQuantity `json:"windDirection"`
+		BarometricPressure    nwsQuantity `json:"barometricPressure"`
+		PrecipitationLastHour nwsQuantity `json:"precipitationLastHour"`
+	} `json:"properties"`
+}
+
+type nwsQuantity struct {
+	Value *float64 `json:"value"`
+}
+
+func (q nwsQuantity) orZero() float64 {
+	if q.Value == nil {
+		return 0
+	}
+	return *q.Value
+}
+
+// nwsBackend fetches current conditions from the National Weather
+// Service's api.weather.gov, resolving the nearest observation station
+// for a configured lat/lon on every fetch (the station list can change
+// as stations go offline).
+type nwsBackend struct {
+	lat float64
+	lon float64
+}
+
+// newNWSBackend reads the lat/lon secrets used to resolve the NWS
+// gridpoint and nearest observation station.
+func newNWSBackend() (*nwsBackend, error) {
+	latStr, err := readSecret("lat")
+	if err != nil {
+		return nil, err
+	}
+	lonStr, err := readSecret("lon")
+	if err != nil {
+		return nil, err
+	}
+
+	lat, err := strconv.ParseFloat(latStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lat secret %q: %v", latStr, err)
+	}
+	lon, err := strconv.ParseFloat(lonStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lon secret %q: %v", lonStr, err)
+	}
+
+	return &nwsBackend{lat: lat, lon: lon}, nil
+}
+
+func (b *nwsBackend) Fetch(ctx context.Context) (Observation, error) {
+	pointsURL := fmt.Sprintf("https://api.weather.gov/points/%.4f,%.4f", b.lat, b.lon)
+	var points nwsPoints
+	if err := nwsGetJSON(ctx, pointsURL, &points); err != nil {
+		return Observation{}, fmt.Errorf("error looking up NWS gridpoint: %v", err)
+	}
+	if points.Properties.ObservationStations == "" {
+		return Observation{}, fmt.Errorf("NWS points response had no observationStations link")
+	}
+
+	var stations nwsStations
+	if err := nwsGetJSON(ctx, points.Properties.ObservationStations, &stations); err != nil {
+		return Observation{}, fmt.Errorf("error looking up NWS observation stations: %v", err)
+	}
+	if len(stations.ObservationStations) == 0 {
+		return Observation{}, fmt.Errorf("no NWS observation stations found near %.4f,%.4f", b.lat, b.lon)
+	}
+	stationURL := stations.ObservationStations[0]
+	log.Printf("Using nearest NWS station: %s", stationURL)
+
+	var obs nwsObservation
+	if err := nwsGetJSON(ctx, stationURL+"/observations/latest", &obs); err != nil {
+		return Observation{}, fmt.Errorf("error fetching NWS observation: %v", err)
+	}
+
+	obsTime, err := parseNWSTimestamp(obs.Properties.Timestamp)
+	if err != nil {
+		log.Printf("Warning: could not parse NWS observation timestamp %q: %v", obs.Properties.Timestamp, err)
+	}
+
+	p := obs.Properties
+	var feelsLikeC float64
+	if p.HeatIndex.Value != nil {
+		feelsLikeC = p.HeatIndex.orZero()
+	} else if p.WindChill.Value != nil {
+		feelsLikeC = p.WindChill.orZero()
+	} else {
+		feelsLikeC = p.Temperature.orZero()
+	}
+
+	return Observation{
+		StationID:    strings.TrimPrefix(stationURL, "https://api.weather.gov/stations/"),
+		Lat:          b.lat,
+		Lon:          b.lon,
+		TempF:        celsiusToF(p.Temperature.orZero()),
+		FeelsLikeF:   celsiusToF(feelsLikeC),
+		DewpointF:    celsiusToF(p.Dewpoint.orZero()),
+		Humidity:     int(p.RelativeHumidity.orZero()),
+		WindSpeed:    kmhToMph(p.WindSpeed.orZero()),
+		WindDir:      int(p.WindDirection.orZero()),
+		WindGust:     kmhToMph(p.WindGust.orZero()),
+		PressureIn:   pascalToInHg(p.BarometricPressure.orZero()),
+		PrecipRate:   0,
+		PrecipTotal:  mmToIn(p.PrecipitationLastHour.orZero()),
+		ObsTimeUTC:   obsTime,
+		ObsTimeLocal: obsTime.Local().Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+// Forecast fetches the multi-day gridpoint forecast for the backend's
+// configured lat/lon, implementing ForecastProvider.
+func (b *nwsBackend) Forecast(ctx context.Context) ([]ForecastPeriod, error) {
+	pointsURL := fmt.Sprintf("https://api.weather.gov/points/%.4f,%.4f", b.lat, b.lon)
+	var points nwsPoints
+	if err := nwsGetJSON(ctx, pointsURL, &points); err != nil {
+		return nil, fmt.Errorf("error looking up NWS gridpoint: %v", err)
+	}
+	if points.Properties.Forecast == "" {
+		return nil, fmt.Errorf("NWS points response had no forecast link")
+	}
+
+	var forecast nwsForecast
+	if err := nwsGetJSON(ctx, points.Properties.Forecast, &forecast); err != nil {
+		return nil, fmt.Errorf("error fetching NWS forecast: %v", err)
+	}
+
+	periods := make([]ForecastPeriod, 0, len(forecast.Properties.Periods))
+	for _, p := range forecast.Properties.Periods {
+		startTime, err := parseNWSTimestamp(p.StartTime)
+		if err != nil {
+			log.Printf("Warning: could not parse NWS forecast period start time %q: %v", p.StartTime, err)
+		}
+		periods = append(periods, ForecastPeriod{
+			Name:          p.Name,
+			StartTime:     startTime,
+			IsDayTime:     p.IsDaytime,
+			Temperature:   p.Temperature,
+			ShortForecast: p.ShortForecast,
+			WindSpeed:     p.WindSpeed,
+			WindDirection: p.WindDirection,
+		})
+	}
+
+	return periods, nil
+}
+
+// nwsGetJSON issues a GET request against api.weather.gov, which requires
+// an identifying User-Agent, and decodes the JSON response into v.
+func nwsGetJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", nwsUserAgent)
+	req.Header.Set("Accept", "application/geo+json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d from %s: %s", resp.StatusCode, url, string(body))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// readSecret reads and trims a file under /mnt/secrets/.
+func readSecret(name string) (string, error) {
+	content, err := os.ReadFile(fmt.Sprintf("/mnt/secrets/%s", name))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file %s: %v", name, err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// owmResponse is the subset of OpenWeatherMap's /data/2.5/weather
+// response we need.
+type owmResponse struct {
+	Coord struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"coord"`
+	Main struct {
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		Pressure  float64 `json:"pressure"`
+		Humidity  int     `json:"humidity"`
+	} `json:"main"`
+	Wind struct {
+		Speed float64 `json:"speed"`
+		Deg   int     `json:"deg"`
+		Gust  float64 `json:"gust"`
+	} `json:"wind"`
+	Rain struct {
+		OneHour float64 `json:"1h"`
+	} `json:"rain"`
+	Name string `json:"name"`
+	Dt   int64  `json:"dt"`
+}
+
+// openWeatherMapBackend fetches current conditions from OpenWeatherMap's
+// /data/2.5/weather endpoint for a configured lat/lon, using imperial
+// units so the response is already in Fahrenheit/mph.
+type openWeatherMapBackend struct {
+	key string
+	lat string
+	lon string
+}
+
+// newOpenWeatherMapBackend reads the key/lat/lon secrets OpenWeatherMap
+// needs.
+func newOpenWeatherMapBackend() (*openWeatherMapBackend, error) {
+	key, err := readSecret("owm_key")
+	if err != nil {
+		return nil, err
+	}
+	lat, err := readSecret("lat")
+	if err != nil {
+		return nil, err
+	}
+	lon, err := readSecret("lon")
+	if err != nil {
+		return nil, err
+	}
+	return &openWeatherMapBackend{key: key, lat: lat, lon: lon}, nil
+}
+
+func (b *openWeatherMapBackend) Fetch(ctx context.Context) (Observation, error) {
+	url := fmt.Sprintf("https://api.openweathermap.org/data/2.5/weather?lat=%s&lon=%s&units=imperial&appid=%s",
+		b.lat, b.lon, b.key)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return Observation{}, fmt.Errorf("error creating HTTP request: %v", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Observation{}, fmt.Errorf("error making HTTP request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Observation{}, fmt.Errorf("unexpected status %d from OpenWeatherMap", resp.StatusCode)
+	}
+
+	var r owmResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return Observation{}, fmt.Errorf("error parsing OpenWeatherMap response: %v", err)
+	}
+
+	return Observation{
+		StationID:    r.Name,
+		Lat:          r.Coord.Lat,
+		Lon:          r.Coord.Lon,
+		TempF:        int(r.Main.Temp),
+		FeelsLikeF:   int(r.Main.FeelsLike),
+		DewpointF:    approxDewpointF(r.Main.Temp, r.Main.Humidity),
+		Humidity:     r.Main.Humidity,
+		WindSpeed:    int(r.Wind.Speed),
+		WindDir:      r.Wind.Deg,
+		WindGust:     int(r.Wind.Gust),
+		PressureIn:   pascalToInHg(r.Main.Pressure * 100),
+		PrecipRate:   0,
+		PrecipTotal:  mmToIn(r.Rain.OneHour),
+		ObsTimeUTC:   time.Unix(r.Dt, 0).UTC(),
+		ObsTimeLocal: time.Unix(r.Dt, 0).Local().Format("2006-01-02 15:04:05"),
+	}, nil
+}
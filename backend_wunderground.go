@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// wundergroundCurrent mirrors the Weather Underground PWS "current
+// conditions" API response.
+type wundergroundCurrent struct {
+	Observations []struct {
+		StationID         string      `json:"stationID"`
+		ObsTimeUtc        time.Time   `json:"obsTimeUtc"`
+		ObsTimeLocal      string      `json:"obsTimeLocal"`
+		Neighborhood      string      `json:"neighborhood"`
+		SoftwareType      string      `json:"softwareType"`
+		Country           string      `json:"country"`
+		SolarRadiation    float64     `json:"solarRadiation"`
+		Lon               float64     `json:"lon"`
+		RealtimeFrequency interface{} `json:"realtimeFrequency"`
+		Epoch             int         `json:"epoch"`
+		Lat               float64     `json:"lat"`
+		Uv                float64     `json:"uv"`
+		Winddir           int         `json:"winddir"`
+		Humidity          int         `json:"humidity"`
+		QcStatus          int         `json:"qcStatus"`
+		Imperial          struct {
+			Temp        int     `json:"temp"`
+			HeatIndex   int     `json:"heatIndex"`
+			Dewpt       int     `json:"dewpt"`
+			WindChill   int     `json:"windChill"`
+			WindSpeed   int     `json:"windSpeed"`
+			WindGust    int     `json:"windGust"`
+			Pressure    float64 `json:"pressure"`
+			PrecipRate  float64 `json:"precipRate"`
+			PrecipTotal float64 `json:"precipTotal"`
+			Elev        int     `json:"elev"`
+		} `json:"imperial"`
+	} `json:"observations"`
+}
+
+// wundergroundForecast is the subset of Weather Underground's
+// forecast/daily/5day response we need. The API returns parallel arrays
+// keyed by day, with a nested "daypart" object holding the finer-grained
+// (day/night) periods as further parallel arrays.
+type wundergroundForecast struct {
+	DayOfWeek    []string `json:"dayOfWeek"`
+	ValidTimeUtc []int64  `json:"validTimeUtc"`
+	Daypart      []struct {
+		DaypartName          []string `json:"daypartName"`
+		Temperature          []int    `json:"temperature"`
+		Narrative            []string `json:"narrative"`
+		WindSpeed            []int    `json:"windSpeed"`
+		WindDirectionCompass []string `json:"windDirectionCompass"`
+		DayOrNight           []string `json:"dayOrNight"`
+	} `json:"daypart"`
+}
+
+// wundergroundBackend fetches current conditions from the Weather
+// Underground PWS API for a single station.
+type wundergroundBackend struct {
+	api     string
+	sid     string
+	units   string
+	key     string
+	geocode string
+}
+
+// newWundergroundBackend reads the api/sid/units/key secrets that the
+// Weather Underground API needs.
+func newWundergroundBackend() (*wundergroundBackend, error) {
+	secretFiles := map[string]*string{}
+	b := &wundergroundBackend{}
+	secretFiles["api"] = &b.api
+	secretFiles["sid"] = &b.sid
+	secretFiles["units"] = &b.units
+	secretFiles["key"] = &b.key
+
+	for fileName, dest := range secretFiles {
+		filePath := fmt.Sprintf("/mnt/secrets/%s", fileName)
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret file %s: %v", fileName, err)
+		}
+		*dest = strings.TrimSpace(string(content))
+	}
+
+	// geocode (lat,lon) is only needed for the 5-day forecast endpoint,
+	// so its absence doesn't prevent current-conditions fetches.
+	if geocode, err := readSecret("geocode"); err == nil {
+		b.geocode = geocode
+	}
+
+	return b, nil
+}
+
+func (b *wundergroundBackend) Fetch(ctx context.Context) (Observation, error) {
+	url := fmt.Sprintf("%s?stationId=%s&format=json&units=%s&apiKey=%s",
+		b.api,
+		b.sid,
+		b.units,
+		b.key,
+	)
+
+	log.Printf("Making API request to: %s", strings.Replace(url, b.key, "***REDACTED***", 1))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return Observation{}, fmt.Errorf("error creating HTTP request: %v", err)
+	}
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Observation{}, fmt.Errorf("error making HTTP request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	log.Printf("API response status: %d %s", resp.StatusCode, resp.Status)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Observation{}, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	log.Printf("API response body length: %d bytes", len(bodyBytes))
+
+	var responseObject wundergroundCurrent
+	if err := json.Unmarshal(bodyBytes, &responseObject); err != nil {
+		log.Printf("Error unmarshaling JSON: %v", err)
+		return Observation{}, fmt.Errorf("error parsing API response: %v", err)
+	}
+
+	log.Printf("Number of observations in response: %d", len(responseObject.Observations))
+
+	if len(responseObject.Observations) == 0 {
+		return Observation{}, fmt.Errorf("no observations found in API response")
+	}
+
+	obs := responseObject.Observations[0]
+	var feelsLikeF int
+	if obs.Imperial.Temp > 70 {
+		feelsLikeF = obs.Imperial.HeatIndex
+	} else {
+		feelsLikeF = obs.Imperial.WindChill
+	}
+
+	return Observation{
+		StationID:    obs.StationID,
+		Lat:          obs.Lat,
+		Lon:          obs.Lon,
+		TempF:        obs.Imperial.Temp,
+		FeelsLikeF:   feelsLikeF,
+		DewpointF:    obs.Imperial.Dewpt,
+		Humidity:     obs.Humidity,
+		WindSpeed:    obs.Imperial.WindSpeed,
+		WindDir:      obs.Winddir,
+		WindGust:     obs.Imperial.WindGust,
+		PressureIn:   obs.Imperial.Pressure,
+		PrecipRate:   obs.Imperial.PrecipRate,
+		PrecipTotal:  obs.Imperial.PrecipTotal,
+		ObsTimeUTC:   obs.ObsTimeUtc,
+		ObsTimeLocal: obs.ObsTimeLocal,
+	}, nil
+}
+
+// Forecast fetches Weather Underground's 5-day/daypart forecast,
+// implementing ForecastProvider.
+func (b *wundergroundBackend) Forecast(ctx context.Context) ([]ForecastPeriod, error) {
+	if b.geocode == "" {
+		return nil, fmt.Errorf("no geocode secret configured for Weather Underground forecast")
+	}
+
+	url := fmt.Sprintf("https://api.weather.com/v3/wx/forecast/daily/5day?geocode=%s&format=json&units=%s&apiKey=%s",
+		b.geocode, b.units, b.key)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %v", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making HTTP request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	var wf wundergroundForecast
+	if err := json.Unmarshal(bodyBytes, &wf); err != nil {
+		return nil, fmt.Errorf("error parsing forecast response: %v", err)
+	}
+	if len(wf.Daypart) == 0 {
+		return nil, fmt.Errorf("forecast response had no daypart data")
+	}
+
+	dp := wf.Daypart[0]
+	periods := make([]ForecastPeriod, 0, len(dp.DaypartName))
+	for i, name := range dp.DaypartName {
+		if name == "" {
+			continue
+		}
+		var startTime time.Time
+		if i/2 < len(wf.ValidTimeUtc) {
+			startTime = time.Unix(wf.ValidTimeUtc[i/2], 0).UTC()
+		}
+		periods = append(periods, ForecastPeriod{
+			Name:          name,
+			StartTime:     startTime,
+			IsDayTime:     i < len(dp.DayOrNight) && dp.DayOrNight[i] == "D",
+			Temperature:   valueOrZero(dp.Temperature, i),
+			ShortForecast: valueOrZero(dp.Narrative, i),
+			WindSpeed:     fmt.Sprintf("%d mph", valueOrZero(dp.WindSpeed, i)),
+			WindDirection: valueOrZero(dp.WindDirectionCompass, i),
+		})
+	}
+
+	return periods, nil
+}
+
+// valueOrZero returns s[i] if i is in range, otherwise the zero value of T.
+func valueOrZero[T any](s []T, i int) T {
+	if i >= 0 && i < len(s) {
+		return s[i]
+	}
+	var zero T
+	return zero
+}
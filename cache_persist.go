@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// persistedObservation is the on-disk format written to the cache file:
+// the neutral observation plus the observation time it was recorded
+// under, so a reload can re-run the same freshness check fetchWeatherData
+// applies to a live fetch.
+type persistedObservation struct {
+	Observation Observation `json:"observation"`
+	ObsTime     time.Time   `json:"obsTime"`
+}
+
+var errCacheTooOld = fmt.Errorf("cached file is older than the freshness window")
+
+// cacheLocation determines where the on-disk weather cache lives,
+// preferring the CACHE_LOCATION env var and falling back to the
+// /mnt/secrets/cache_location secret file. Returns "" if neither is set,
+// which disables disk persistence.
+func cacheLocation() string {
+	if loc := os.Getenv("CACHE_LOCATION"); loc != "" {
+		return loc
+	}
+	if content, err := os.ReadFile("/mnt/secrets/cache_location"); err == nil {
+		if loc := strings.TrimSpace(string(content)); loc != "" {
+			return loc
+		}
+	}
+	return ""
+}
+
+// saveToDisk writes the observation to the configured cache location so
+// a restarted container can reload it instead of hammering the upstream
+// API. A failure to persist is logged but never surfaces to the caller,
+// since the in-memory cache remains the source of truth.
+func saveToDisk(obs Observation, obsTime time.Time) {
+	loc := cacheLocation()
+	if loc == "" {
+		return
+	}
+
+	data, err := json.Marshal(persistedObservation{Observation: obs, ObsTime: obsTime})
+	if err != nil {
+		log.Printf("Warning: failed to marshal weather cache for disk: %v", err)
+		return
+	}
+
+	if err := os.WriteFile(loc, data, 0o644); err != nil {
+		log.Printf("Warning: failed to write weather cache to %s: %v", loc, err)
+		return
+	}
+
+	log.Printf("Persisted weather cache to %s", loc)
+}
+
+// loadFromDisk reads the on-disk weather cache, returning errCacheTooOld
+// if the file's mod time is outside dataFreshnessWindow. It also
+// returns the file's mod time so the caller can seed cache.lastFetched
+// and keep the 30-minute rate limit honest across a restart.
+func loadFromDisk() (Observation, time.Time, time.Time, error) {
+	loc := cacheLocation()
+	if loc == "" {
+		return Observation{}, time.Time{}, time.Time{}, fmt.Errorf("no CACHE_LOCATION configured")
+	}
+
+	info, err := os.Stat(loc)
+	if err != nil {
+		return Observation{}, time.Time{}, time.Time{}, fmt.Errorf("cache file not found: %v", err)
+	}
+
+	age := time.Since(info.ModTime())
+	if age > dataFreshnessWindow {
+		return Observation{}, time.Time{}, time.Time{}, errCacheTooOld
+	}
+
+	content, err := os.ReadFile(loc)
+	if err != nil {
+		return Observation{}, time.Time{}, time.Time{}, fmt.Errorf("failed to read cache file: %v", err)
+	}
+
+	var persisted persistedObservation
+	if err := json.Unmarshal(content, &persisted); err != nil {
+		return Observation{}, time.Time{}, time.Time{}, fmt.Errorf("failed to parse cache file: %v", err)
+	}
+
+	log.Printf("Loaded weather cache from disk (%s old)", age.Round(time.Second))
+	return persisted.Observation, persisted.ObsTime, info.ModTime(), nil
+}
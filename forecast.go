@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ForecastPeriod is one backend-neutral period of a multi-day forecast,
+// shaped after NWS's gridpoint forecast periods.
+type ForecastPeriod struct {
+	Name          string
+	StartTime     time.Time
+	IsDayTime     bool
+	Temperature   int
+	ShortForecast string
+	WindSpeed     string
+	WindDirection string
+}
+
+// ForecastProvider is implemented by backends that can supply a
+// multi-day forecast in addition to current conditions.
+type ForecastProvider interface {
+	Forecast(ctx context.Context) ([]ForecastPeriod, error)
+}
+
+// forecastFreshnessWindow is much longer than the current-conditions
+// window since forecasts change far less often than observations.
+const forecastFreshnessWindow = 3 * time.Hour
+
+// forecastCache caches the most recently fetched forecast periods,
+// independent of the current-conditions cache.
+type forecastCache struct {
+	periods     []ForecastPeriod
+	lastFetched time.Time
+	mu          sync.RWMutex
+}
+
+var fCache = &forecastCache{}
+
+// getCachedForecast returns the cached forecast, fetching a new one if
+// it's older than forecastFreshnessWindow or hasn't been fetched yet. If
+// the configured backend doesn't implement ForecastProvider, it returns
+// an empty slice.
+func getCachedForecast() ([]ForecastPeriod, error) {
+	provider, ok := backend.(ForecastProvider)
+	if !ok {
+		return nil, nil
+	}
+
+	fCache.mu.RLock()
+	fresh := !fCache.lastFetched.IsZero() && time.Since(fCache.lastFetched) < forecastFreshnessWindow
+	periods := fCache.periods
+	fCache.mu.RUnlock()
+	if fresh {
+		return periods, nil
+	}
+
+	log.Printf("Fetching new forecast data")
+	fetched, err := provider.Forecast(context.Background())
+	if err != nil {
+		if !fCache.lastFetched.IsZero() {
+			log.Printf("Forecast fetch failed, returning stale cached forecast: %v", err)
+			return periods, nil
+		}
+		return nil, fmt.Errorf("error fetching forecast: %v", err)
+	}
+
+	fCache.mu.Lock()
+	fCache.periods = fetched
+	fCache.lastFetched = time.Now()
+	fCache.mu.Unlock()
+
+	return fetched, nil
+}
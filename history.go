@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// historyPath is the append-only JSONL file each successfully fetched
+// observation is recorded to.
+const historyPath = "/var/lib/ocp-pws/history.jsonl"
+
+// defaultHistoryRetentionDays bounds how long history.jsonl is kept;
+// with a 30-minute fetch interval even a generous window is tiny.
+const defaultHistoryRetentionDays = 30
+
+// historyEntry is one line of history.jsonl.
+type historyEntry struct {
+	Time        time.Time   `json:"time"`
+	Observation Observation `json:"observation"`
+}
+
+var historyMu sync.RWMutex
+
+// recordHistory appends obs to history.jsonl. A failure is logged but
+// never propagated, since history is a best-effort side record of
+// otherwise-successful fetches.
+func recordHistory(obs Observation, at time.Time) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	if err := os.MkdirAll("/var/lib/ocp-pws", 0o755); err != nil {
+		log.Printf("Warning: failed to create history directory: %v", err)
+		return
+	}
+
+	f, err := os.OpenFile(historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Printf("Warning: failed to open history file: %v", err)
+		return
+	}
+	defer f.Close()
+
+	entry := historyEntry{Time: at, Observation: obs}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Warning: failed to marshal history entry: %v", err)
+		return
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		log.Printf("Warning: failed to append history entry: %v", err)
+	}
+}
+
+// readHistory reads all recorded history entries from the oldest
+// surviving one forward. It takes historyMu for reading so it can't
+// observe a torn file mid-append (recordHistory) or mid-rewrite
+// (pruneHistory).
+func readHistory() ([]historyEntry, error) {
+	historyMu.RLock()
+	defer historyMu.RUnlock()
+
+	f, err := os.Open(historyPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %v", err)
+	}
+	defer f.Close()
+
+	var entries []historyEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Printf("Warning: skipping malformed history line: %v", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading history file: %v", err)
+	}
+
+	return entries, nil
+}
+
+// historyRetentionDays determines how many days of history to keep,
+// configurable via HISTORY_RETENTION_DAYS.
+func historyRetentionDays() int {
+	if env := os.Getenv("HISTORY_RETENTION_DAYS"); env != "" {
+		if days, err := strconv.Atoi(env); err == nil && days > 0 {
+			return days
+		}
+		log.Printf("Invalid HISTORY_RETENTION_DAYS env var, using default: %d days", defaultHistoryRetentionDays)
+	}
+	return defaultHistoryRetentionDays
+}
+
+// pruneHistory rewrites history.jsonl keeping only entries within the
+// configured retention window. It's meant to run once at startup.
+func pruneHistory() {
+	entries, err := readHistory()
+	if err != nil {
+		log.Printf("Warning: could not read history file to prune: %v", err)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -historyRetentionDays())
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.Time.After(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(entries) {
+		return
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	f, err := os.Create(historyPath)
+	if err != nil {
+		log.Printf("Warning: could not rewrite history file while pruning: %v", err)
+		return
+	}
+	defer f.Close()
+
+	for _, e := range kept {
+		line, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		f.Write(append(line, '\n'))
+	}
+
+	log.Printf("Pruned history file: kept %d of %d entries (retention %d days)", len(kept), len(entries), historyRetentionDays())
+}
+
+// trends holds the derived fields the template displays alongside the
+// current observation.
+type trends struct {
+	PressureTrend string
+	TempHigh24H   int
+	TempLow24H    int
+	Precip1H      float64
+}
+
+// computeTrends derives the pressure tendency, 24-hour high/low, and
+// 1-hour precip accumulation from recorded history plus the current
+// observation.
+func computeTrends(current Observation, now time.Time) trends {
+	entries, err := readHistory()
+	if err != nil {
+		log.Printf("Warning: could not compute trends: %v", err)
+		return trends{PressureTrend: "unknown", TempHigh24H: current.TempF, TempLow24H: current.TempF}
+	}
+
+	t := trends{
+		PressureTrend: "steady",
+		TempHigh24H:   current.TempF,
+		TempLow24H:    current.TempF,
+	}
+
+	var pressure3hAgo *float64
+	var precip1hAgo *float64
+	closestTo := func(target time.Time) *historyEntry {
+		var best *historyEntry
+		var bestDelta time.Duration
+		for i := range entries {
+			e := entries[i]
+			delta := target.Sub(e.Time)
+			if delta < 0 {
+				delta = -delta
+			}
+			if best == nil || delta < bestDelta {
+				best, bestDelta = &entries[i], delta
+			}
+		}
+		return best
+	}
+
+	if e := closestTo(now.Add(-3 * time.Hour)); e != nil {
+		v := e.Observation.PressureIn
+		pressure3hAgo = &v
+	}
+	if e := closestTo(now.Add(-1 * time.Hour)); e != nil {
+		v := e.Observation.PrecipTotal
+		precip1hAgo = &v
+	}
+
+	for _, e := range entries {
+		if now.Sub(e.Time) > 24*time.Hour {
+			continue
+		}
+		if e.Observation.TempF > t.TempHigh24H {
+			t.TempHigh24H = e.Observation.TempF
+		}
+		if e.Observation.TempF < t.TempLow24H {
+			t.TempLow24H = e.Observation.TempF
+		}
+	}
+
+	if pressure3hAgo != nil {
+		// Mirrors METAR tendency convention: a 0.02 inHg deadband counts as steady.
+		delta := current.PressureIn - *pressure3hAgo
+		switch {
+		case delta > 0.02:
+			t.PressureTrend = fmt.Sprintf("rising (+%.2f inHg)", delta)
+		case delta < -0.02:
+			t.PressureTrend = fmt.Sprintf("falling (%.2f inHg)", delta)
+		default:
+			t.PressureTrend = "steady"
+		}
+	} else {
+		t.PressureTrend = "unknown"
+	}
+
+	if precip1hAgo != nil {
+		t.Precip1H = current.PrecipTotal - *precip1hAgo
+		if t.Precip1H < 0 {
+			t.Precip1H = 0
+		}
+	}
+
+	return t
+}
+
+// registerHistoryHandler wires up /api/history?hours=N, returning the
+// recorded observations from the last N hours (default 24).
+func registerHistoryHandler() {
+	http.HandleFunc("/api/history", func(w http.ResponseWriter, r *http.Request) {
+		hours := 24
+		if h := r.URL.Query().Get("hours"); h != "" {
+			if parsed, err := strconv.Atoi(h); err == nil && parsed > 0 {
+				hours = parsed
+			}
+		}
+
+		entries, err := readHistory()
+		if err != nil {
+			log.Printf("Error reading history for /api/history: %v", err)
+			http.Error(w, "History data unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		cutoff := time.Now().UTC().Add(-time.Duration(hours) * time.Hour)
+		var filtered []historyEntry
+		for _, e := range entries {
+			if e.Time.After(cutoff) {
+				filtered = append(filtered, e)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(filtered); err != nil {
+			log.Printf("Error encoding /api/history response: %v", err)
+		}
+	})
+}
@@ -1,10 +1,10 @@
 package main
 
 import (
+	"context"
 	"embed"
-	"encoding/json"
 	"fmt"
-	"io"
+	"html/template"
 	"io/fs"
 	"log"
 	"net/http"
@@ -12,7 +12,6 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"text/template"
 	"time"
 )
 
@@ -22,69 +21,44 @@ var staticFiles embed.FS
 //go:embed templates/*
 var templateFiles embed.FS
 
-type weatherCurrent struct {
-	Observations []struct {
-		StationID         string      `json:"stationID"`
-		ObsTimeUtc        time.Time   `json:"obsTimeUtc"`
-		ObsTimeLocal      string      `json:"obsTimeLocal"`
-		Neighborhood      string      `json:"neighborhood"`
-		SoftwareType      string      `json:"softwareType"`
-		Country           string      `json:"country"`
-		SolarRadiation    float64     `json:"solarRadiation"`
-		Lon               float64     `json:"lon"`
-		RealtimeFrequency interface{} `json:"realtimeFrequency"`
-		Epoch             int         `json:"epoch"`
-		Lat               float64     `json:"lat"`
-		Uv                float64     `json:"uv"`
-		Winddir           int         `json:"winddir"`
-		Humidity          int         `json:"humidity"`
-		QcStatus          int         `json:"qcStatus"`
-		Imperial          struct {
-			Temp        int     `json:"temp"`
-			HeatIndex   int     `json:"heatIndex"`
-			Dewpt       int     `json:"dewpt"`
-			WindChill   int     `json:"windChill"`
-			WindSpeed   int     `json:"windSpeed"`
-			WindGust    int     `json:"windGust"`
-			Pressure    float64 `json:"pressure"`
-			PrecipRate  float64 `json:"precipRate"`
-			PrecipTotal float64 `json:"precipTotal"`
-			Elev        int     `json:"elev"`
-		} `json:"imperial"`
-	} `json:"observations"`
-}
-
 // Index holds fields displayed on the index.html template
 type Index struct {
-	StationID    string
-	ReportTime   string
-	CurrentTempF int
-	CurrentTempC int
-	FeelsLikeF   int
-	FeelsLikeC   int
-	DewPointF    int
-	DewPointC    int
-	Humidity     int
-	WindSpeed    int
-	WindGust     int
-	WindDirC     string
-	WindDirD     int
-	RandomSecret string
+	StationID     string
+	ReportTime    string
+	CurrentTempF  int
+	CurrentTempC  int
+	FeelsLikeF    int
+	FeelsLikeC    int
+	DewPointF     int
+	DewPointC     int
+	Humidity      int
+	WindSpeed     int
+	WindGust      int
+	WindDirC      string
+	WindDirD      int
+	Forecast      []ForecastPeriod
+	Alerts        []Alert
+	PressureTrend string
+	TempHigh24H   int
+	TempLow24H    int
+	Precip1H      float64
+	RandomSecret  string
 }
 
 var httpClient = &http.Client{
 	Timeout: 10 * time.Second,
 }
 
-// Global variables for API configuration read at startup
-var api, sid, units, key string
+// backend is the configured WeatherBackend, selected at startup via the
+// BACKEND env var / secret file.
+var backend WeatherBackend
 
 // Configurable buffer time (default 30 seconds)
 var fetchBufferSeconds = 30
 
 // Cache for weather data
 type weatherCache struct {
-	data        weatherCurrent
+	data        Observation
 	lastFetched time.Time
 	dataAge     time.Time // Track the actual observation time
 	mu          sync.RWMutex
@@ -93,21 +67,13 @@ type weatherCache struct {
 var cache = &weatherCache{}
 
 func readAPIConfig() error {
-	secretFiles := map[string]*string{
-		"api":   &api,
-		"sid":   &sid,
-		"units": &units,
-		"key":   &key,
-	}
-
-	for fileName, envVar := range secretFiles {
-		filePath := fmt.Sprintf("/mnt/secrets/%s", fileName)
-		content, err := os.ReadFile(filePath)
-		if err != nil {
-			return fmt.Errorf("failed to read secret file %s: %v", fileName, err)
-		}
-		*envVar = strings.TrimSpace(string(content))
+	name := readBackendName()
+	b, err := newBackend(name)
+	if err != nil {
+		return fmt.Errorf("failed to configure weather backend %q: %v", name, err)
 	}
+	backend = b
+	log.Printf("Using weather backend: %s", name)
 
 	// Configure fetch buffer (priority: env var > file > default)
 	if envBuffer := os.Getenv("FETCH_BUFFER_SECONDS"); envBuffer != "" {
@@ -139,12 +105,17 @@ func readRandomSecret() (string, error) {
 	return strings.TrimSpace(string(content)), nil
 }
 
+// dataFreshnessWindow is how old an observation (fetched live or reloaded
+// from disk) is allowed to be before it's considered stale. With
+// 30-minute fetch intervals, 35 minutes gives a little slack.
+const dataFreshnessWindow = 35 * time.Minute
+
 // isDataFresh checks if the observation data is reasonably current
 // With 30-minute fetch intervals, we accept data up to 35 minutes old
 func isDataFresh(obsTimeUtc time.Time) (bool, time.Time, error) {
 	now := time.Now().UTC()
 	age := now.Sub(obsTimeUtc)
-	isFresh := age <= 35*time.Minute
+	isFresh := age <= dataFreshnessWindow
 
 	log.Printf("Data observation time (UTC): %s, current time (UTC): %s, age: %v, fresh: %t",
 		obsTimeUtc.Format("15:04:05"), now.Format("15:04:05"), age, isFresh)
@@ -174,82 +145,66 @@ func shouldFetchNewData(t time.Time) bool {
 	return true
 }
 
-// fetchWeatherData fetches weather data from the API and caches it
-func fetchWeatherData() (weatherCurrent, error) {
-	url := fmt.Sprintf("%s?stationId=%s&format=json&units=%s&apiKey=%s",
-		api,
-		sid,
-		units,
-		key,
-	)
-
-	log.Printf("Making API request to: %s", strings.Replace(url, key, "***REDACTED***", 1))
-
-	req, err := http.NewRequest("GET", url, nil)
+// fetchWeatherData fetches weather data from the configured backend and caches it
+func fetchWeatherData() (Observation, error) {
+	fetchStart := time.Now()
+	obs, err := backend.Fetch(context.Background())
 	if err != nil {
-		return weatherCurrent{}, fmt.Errorf("error creating HTTP request: %v", err)
-	}
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
-
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return weatherCurrent{}, fmt.Errorf("error making HTTP request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	log.Printf("API response status: %d %s", resp.StatusCode, resp.Status)
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return weatherCurrent{}, fmt.Errorf("error reading response body: %v", err)
-	}
-
-	log.Printf("API response body length: %d bytes", len(bodyBytes))
-	if len(bodyBytes) > 0 {
-		log.Printf("Raw API response: %s", string(bodyBytes))
-	}
-
-	var responseObject weatherCurrent
-	if err := json.Unmarshal(bodyBytes, &responseObject); err != nil {
-		log.Printf("Error unmarshaling JSON: %v", err)
-		log.Printf("Raw response that failed to unmarshal: %s", string(bodyBytes))
-		return weatherCurrent{}, fmt.Errorf("error parsing API response: %v", err)
-	}
-
-	log.Printf("Number of observations in response: %d", len(responseObject.Observations))
-
-	if len(responseObject.Observations) == 0 {
-		return weatherCurrent{}, fmt.Errorf("no observations found in API response")
+		recordFetchMetrics("error", time.Since(fetchStart), Observation{})
+		return Observation{}, fmt.Errorf("error fetching weather data: %v", err)
 	}
 
 	// Check if the returned data is fresh
-	obs := responseObject.Observations[0]
-	isFresh, obsTime, err := isDataFresh(obs.ObsTimeUtc)
+	isFresh, obsTime, err := isDataFresh(obs.ObsTimeUTC)
 	if err != nil {
 		log.Printf("Warning: Could not determine data freshness: %v", err)
 	}
 
 	// Cache the data with observation time
 	cache.mu.Lock()
-	cache.data = responseObject
+	cache.data = obs
 	cache.lastFetched = time.Now()
 	if err == nil {
 		cache.dataAge = obsTime
 	}
 	cache.mu.Unlock()
 
+	if isFresh {
+		saveToDisk(obs, obsTime)
+		recordHistory(obs, obsTime)
+		recordFetchMetrics("ok", time.Since(fetchStart), obs)
+	} else {
+		recordFetchMetrics("stale", time.Since(fetchStart), obs)
+	}
+
 	if !isFresh {
-		return weatherCurrent{}, fmt.Errorf("API returned stale data (observation time UTC: %s)", obs.ObsTimeUtc.Format(time.RFC3339))
+		return Observation{}, fmt.Errorf("backend returned stale data (observation time UTC: %s)", obs.ObsTimeUTC.Format(time.RFC3339))
 	}
 
-	return responseObject, nil
+	return obs, nil
 }
 
 // getCachedWeatherData returns cached weather data or fetches new data if needed
-func getCachedWeatherData() (weatherCurrent, error) {
+func getCachedWeatherData() (Observation, error) {
 	now := time.Now()
 
+	// If we have nothing in memory yet (e.g. just after a restart), try to
+	// reload a still-fresh on-disk cache before considering a network fetch.
+	cache.mu.RLock()
+	cacheEmpty := cache.lastFetched.IsZero()
+	cache.mu.RUnlock()
+	if cacheEmpty {
+		if obs, obsTime, modTime, err := loadFromDisk(); err == nil {
+			cache.mu.Lock()
+			cache.data = obs
+			cache.dataAge = obsTime
+			cache.lastFetched = modTime
+			cache.mu.Unlock()
+		} else if err != errCacheTooOld {
+			log.Printf("No usable on-disk weather cache: %v", err)
+		}
+	}
+
 	// Check if we should fetch new data (respects 30-minute minimum interval)
 	if shouldFetchNewData(now) {
 		log.Printf("Fetching new weather data at %s", now.Format("15:04:05"))
@@ -265,7 +220,7 @@ func getCachedWeatherData() (weatherCurrent, error) {
 				return cachedData, nil
 			}
 			cache.mu.RUnlock()
-			return weatherCurrent{}, err
+			return Observation{}, err
 		}
 		return data, nil
 	}
@@ -281,7 +236,7 @@ func getCachedWeatherData() (weatherCurrent, error) {
 	}
 	cache.mu.RUnlock()
 
-	return weatherCurrent{}, fmt.Errorf("no cached weather data available")
+	return Observation{}, fmt.Errorf("no cached weather data available")
 }
 
 func main() {
@@ -290,6 +245,8 @@ func main() {
 		log.Fatal("Configuration error:", err)
 	}
 
+	pruneHistory()
+
 	// Debug printing of Environment
 	if _, ok := os.LookupEnv("DEBUG"); ok {
 		for _, element := range os.Environ() {
@@ -305,6 +262,10 @@ func main() {
 	}
 	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.FS(staticFS))))
 
+	registerAPIHandlers()
+	registerHistoryHandler()
+	registerMetricsHandler()
+
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		// Read only the random secret on each request
 		rsec, err := readRandomSecret()
@@ -320,7 +281,7 @@ func main() {
 		w.Header().Set("Expires", "0")
 
 		// Get weather data (cached or fresh)
-		responseObject, err := getCachedWeatherData()
+		obs, err := getCachedWeatherData()
 		if err != nil {
 			log.Printf("Error getting weather data: %v", err)
 			http.Error(w, "Weather data unavailable", http.StatusServiceUnavailable)
@@ -328,41 +289,39 @@ func main() {
 		}
 
 		if _, ok := os.LookupEnv("DEBUG"); ok {
-			fmt.Fprintf(w, "API Response as struct %+v\n", responseObject)
+			fmt.Fprintf(w, "Observation as struct %+v\n", obs)
 		}
 
-		obs := responseObject.Observations[0]
 		log.Printf("Processing observation from station: %s, time: %s", obs.StationID, obs.ObsTimeLocal)
-		var feelsLikeF, feelsLikeC int
-		if obs.Imperial.Temp > 70 {
-			feelsLikeF = obs.Imperial.HeatIndex
-			feelsLikeC = (((obs.Imperial.HeatIndex - 32) * 5) / 9)
-		} else {
-			feelsLikeF = obs.Imperial.WindChill
-			feelsLikeC = (((obs.Imperial.WindChill - 32) * 5) / 9)
-		}
-		compassDirs := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW", "N"}
-		compassIndex := obs.Winddir / 22
 
-		// Ensure compass index is within bounds
-		if compassIndex >= len(compassDirs) {
-			compassIndex = len(compassDirs) - 1
+		forecast, err := getCachedForecast()
+		if err != nil {
+			log.Printf("Warning: could not get forecast data: %v", err)
 		}
 
+		alerts := getCachedAlerts(obs.Lat, obs.Lon)
+		trend := computeTrends(obs, time.Now().UTC())
+
 		index := Index{
 			obs.StationID,
 			obs.ObsTimeLocal,
-			obs.Imperial.Temp,
-			(((obs.Imperial.Temp - 32) * 5) / 9),
-			feelsLikeF,
-			feelsLikeC,
-			obs.Imperial.Dewpt,
-			(((obs.Imperial.Dewpt - 32) * 5) / 9),
+			obs.TempF,
+			(((obs.TempF - 32) * 5) / 9),
+			obs.FeelsLikeF,
+			(((obs.FeelsLikeF - 32) * 5) / 9),
+			obs.DewpointF,
+			(((obs.DewpointF - 32) * 5) / 9),
 			obs.Humidity,
-			obs.Imperial.WindSpeed,
-			obs.Imperial.WindGust,
-			compassDirs[compassIndex],
-			obs.Winddir,
+			obs.WindSpeed,
+			obs.WindGust,
+			obs.CompassDir(),
+			obs.WindDir,
+			forecast,
+			alerts,
+			trend.PressureTrend,
+			trend.TempHigh24H,
+			trend.TempLow24H,
+			trend.Precip1H,
 			rsec,
 		}
 
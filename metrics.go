@@ -0,0 +1,95 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	fetchTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ocp_pws_fetch_total",
+		Help: "Total number of upstream weather fetch attempts, by result.",
+	}, []string{"result"})
+
+	fetchDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "ocp_pws_fetch_duration_seconds",
+		Help: "Duration of upstream weather backend fetches.",
+	})
+
+	// cacheAgeSeconds is computed at scrape time rather than set on each
+	// fetch, so it keeps climbing during an upstream outage instead of
+	// freezing at the last successful fetch's (always-fresh) age.
+	cacheAgeSeconds = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "ocp_pws_cache_age_seconds",
+		Help: "Age in seconds of the current observation relative to now.",
+	}, func() float64 {
+		cache.mu.RLock()
+		dataAge := cache.dataAge
+		cache.mu.RUnlock()
+		if dataAge.IsZero() {
+			return 0
+		}
+		return time.Since(dataAge).Seconds()
+	})
+
+	observationTempF = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ocp_pws_observation_temp_f",
+		Help: "Most recently observed temperature in Fahrenheit.",
+	})
+
+	windSpeedMph = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ocp_pws_wind_speed_mph",
+		Help: "Most recently observed wind speed in miles per hour.",
+	})
+
+	humidityPercent = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ocp_pws_humidity_percent",
+		Help: "Most recently observed relative humidity, percent.",
+	})
+
+	lastFetchTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ocp_pws_last_fetch_timestamp_seconds",
+		Help: "Unix timestamp of the last upstream fetch attempt, successful or not.",
+	})
+)
+
+// recordFetchMetrics updates the Prometheus metrics for a single
+// fetchWeatherData attempt. result is one of "ok", "error", or "stale".
+func recordFetchMetrics(result string, duration time.Duration, obs Observation) {
+	fetchTotal.WithLabelValues(result).Inc()
+	fetchDuration.Observe(duration.Seconds())
+	lastFetchTimestamp.Set(float64(time.Now().Unix()))
+
+	if result == "error" {
+		return
+	}
+
+	observationTempF.Set(float64(obs.TempF))
+	windSpeedMph.Set(float64(obs.WindSpeed))
+	humidityPercent.Set(float64(obs.Humidity))
+}
+
+// metricsToken returns the optional METRICS_TOKEN used to gate /metrics
+// for public deployments. Empty means the endpoint is open.
+func metricsToken() string {
+	return os.Getenv("METRICS_TOKEN")
+}
+
+// registerMetricsHandler wires up /metrics, requiring a matching
+// X-Metrics-Token header when METRICS_TOKEN is configured.
+func registerMetricsHandler() {
+	handler := promhttp.Handler()
+
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		if token := metricsToken(); token != "" && r.Header.Get("X-Metrics-Token") != token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,41 @@
+package main
+
+import "time"
+
+// Observation is the backend-neutral representation of a single weather
+// reading. Every WeatherBackend implementation maps its provider's native
+// response into this shape so the rest of the app never needs to know
+// which provider produced the data.
+type Observation struct {
+	StationID    string
+	Lat          float64
+	Lon          float64
+	TempF        int
+	FeelsLikeF   int
+	DewpointF    int
+	Humidity     int
+	WindSpeed    int
+	WindDir      int
+	WindGust     int
+	PressureIn   float64
+	PrecipRate   float64
+	PrecipTotal  float64
+	ObsTimeUTC   time.Time
+	ObsTimeLocal string
+}
+
+// compassDirs maps a 0-360 degree wind direction onto the 16-point compass.
+var compassDirs = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW", "N"}
+
+// CompassDir returns the 16-point compass direction for the observation's
+// wind direction in degrees.
+func (o Observation) CompassDir() string {
+	idx := o.WindDir / 22
+	if idx >= len(compassDirs) {
+		idx = len(compassDirs) - 1
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	return compassDirs[idx]
+}
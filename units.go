@@ -0,0 +1,58 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// celsiusToF converts Celsius to whole-degree Fahrenheit, matching the
+// integer Fahrenheit values the Weather Underground API returns.
+func celsiusToF(c float64) int {
+	return int(c*9/5 + 32)
+}
+
+// kmhToMph converts kilometers per hour to whole-number miles per hour.
+func kmhToMph(kmh float64) int {
+	return int(kmh * 0.621371)
+}
+
+// pascalToInHg converts pascals to inches of mercury.
+func pascalToInHg(pa float64) float64 {
+	return pa * 0.0002953
+}
+
+// mmToIn converts millimeters to inches.
+func mmToIn(mm float64) float64 {
+	return mm * 0.0393701
+}
+
+// approxDewpointF estimates the dewpoint in Fahrenheit from temperature
+// and relative humidity using the Magnus formula, for providers (like
+// OpenWeatherMap's free tier) that don't report dewpoint directly. A
+// degraded response can report humidity as 0, which would send the
+// Magnus formula's log term to -Inf and the final int conversion to
+// undefined behavior; clamp humidity to a sane range first and fall
+// back to the air temperature when humidity is missing entirely.
+func approxDewpointF(tempF float64, humidity int) int {
+	if humidity <= 0 {
+		return int(tempF)
+	}
+	if humidity > 100 {
+		humidity = 100
+	}
+
+	const a, b = 17.62, 243.12
+	tempC := (tempF - 32) * 5 / 9
+	gamma := math.Log(float64(humidity)/100) + (a*tempC)/(b+tempC)
+	dewC := (b * gamma) / (a - gamma)
+	return int(dewC*9/5 + 32)
+}
+
+// parseNWSTimestamp parses the RFC3339 timestamps used throughout the
+// api.weather.gov JSON responses.
+func parseNWSTimestamp(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestCelsiusToF(t *testing.T) {
+	cases := []struct {
+		c    float64
+		want int
+	}{
+		{0, 32},
+		{100, 212},
+		{-40, -40},
+	}
+	for _, tc := range cases {
+		if got := celsiusToF(tc.c); got != tc.want {
+			t.Errorf("celsiusToF(%v) = %d, want %d", tc.c, got, tc.want)
+		}
+	}
+}
+
+func TestKmhToMph(t *testing.T) {
+	if got := kmhToMph(0); got != 0 {
+		t.Errorf("kmhToMph(0) = %d, want 0", got)
+	}
+	if got := kmhToMph(100); got != 62 {
+		t.Errorf("kmhToMph(100) = %d, want 62", got)
+	}
+}
+
+func TestPascalToInHg(t *testing.T) {
+	got := pascalToInHg(101325)
+	if got < 29.9 || got > 30.0 {
+		t.Errorf("pascalToInHg(101325) = %v, want ~29.92", got)
+	}
+}
+
+func TestMmToIn(t *testing.T) {
+	got := mmToIn(25.4)
+	if got < 0.99 || got > 1.01 {
+		t.Errorf("mmToIn(25.4) = %v, want ~1.0", got)
+	}
+}
+
+func TestApproxDewpointF(t *testing.T) {
+	cases := []struct {
+		name     string
+		tempF    float64
+		humidity int
+		want     int
+	}{
+		{"zero humidity falls back to temp", 72, 0, 72},
+		{"negative humidity falls back to temp", 50, -5, 50},
+		{"100% humidity equals temp", 68, 100, 68},
+		{"over 100% clamps to 100%", 68, 150, 68},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := approxDewpointF(tc.tempF, tc.humidity)
+			if got != tc.want {
+				t.Errorf("approxDewpointF(%v, %d) = %d, want %d", tc.tempF, tc.humidity, got, tc.want)
+			}
+		})
+	}
+
+	// A degraded/zero-humidity response must never produce the
+	// int(-Inf) underflow this guard was added to fix.
+	if got := approxDewpointF(72, 0); got < -1000 {
+		t.Fatalf("approxDewpointF(72, 0) = %d, looks like an unguarded -Inf conversion", got)
+	}
+}